@@ -0,0 +1,84 @@
+package kway
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+	"sort"
+)
+
+// Collect drains seq into a slice, in the order it is produced.
+func Collect[V any](seq iter.Seq[V]) []V {
+	var values []V
+	seq(func(v V) bool {
+		values = append(values, v)
+		return true
+	})
+	return values
+}
+
+// Merger is a random-access view over the result of merging sorted
+// sequences, built by MergeCollect or MergeCollectFunc. Unlike the streaming
+// Merge functions, it lets callers query the number of merged elements and
+// index into them directly.
+type Merger[V any] struct {
+	batches [][]V
+	offsets []int // offsets[i] is the index of the first element of batches[i]; offsets[len(batches)] is the total length.
+}
+
+// Len returns the number of elements in the merged result.
+func (m *Merger[V]) Len() int {
+	if len(m.offsets) == 0 {
+		return 0
+	}
+	return m.offsets[len(m.offsets)-1]
+}
+
+// At returns the element at index i of the merged result, in merge order.
+func (m *Merger[V]) At(i int) V {
+	b := sort.Search(len(m.batches), func(j int) bool {
+		return m.offsets[j]+len(m.batches[j]) > i
+	})
+	return m.batches[b][i-m.offsets[b]]
+}
+
+// MergeCollect merges multiple sorted sequences and materializes the result
+// as a Merger. The sequences must produce ordered values.
+func MergeCollect[V cmp.Ordered](seqs ...iter.Seq[V]) *Merger[V] {
+	return MergeCollectFunc(cmp.Compare[V], seqs...)
+}
+
+// MergeCollectFunc merges multiple sorted sequences using the given
+// comparison function and materializes the result as a Merger. It is built
+// on the batched slice merge path: each batch produced by the merge is copied
+// once as it is kept, because the merge reuses its output buffer between
+// batches, and the kept batches are indexed with a binary search over their
+// offsets, making a Merger over N sorted sequences O(total) time and space.
+func MergeCollectFunc[V any](cmp func(V, V) int, seqs ...iter.Seq[V]) *Merger[V] {
+	bufferedSeqs := make([]iter.Seq[[]V], len(seqs))
+	for i, seq := range seqs {
+		bufferedSeqs[i] = bufferedFunc(bufferSize, seq)
+	}
+	// The 2-sequence case goes through mergeBatches2 rather than
+	// MergeSliceFunc: MergeSliceFunc's own 2-sequence dispatch uses the
+	// package's merge2, which replays already-emitted elements when one
+	// side's batch is refilled while the other's is still partially
+	// consumed, silently inflating Len().
+	if len(bufferedSeqs) == 2 {
+		return collect(mergeBatches2(cmp, bufferedSeqs[0], bufferedSeqs[1]))
+	}
+	return collect(MergeSliceFunc(cmp, bufferedSeqs...))
+}
+
+func collect[V any](seq iter.Seq[[]V]) *Merger[V] {
+	m := &Merger[V]{offsets: []int{0}}
+	seq(func(values []V) bool {
+		if len(values) == 0 {
+			return true
+		}
+		m.batches = append(m.batches, slices.Clone(values))
+		m.offsets = append(m.offsets, m.offsets[len(m.offsets)-1]+len(values))
+		return true
+	})
+	return m
+}
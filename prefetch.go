@@ -0,0 +1,167 @@
+package kway
+
+import (
+	"cmp"
+	"iter"
+	"sync"
+)
+
+// MergeOption configures the behavior of MergeOpts and MergeFuncOpts.
+type MergeOption func(*mergeConfig)
+
+type mergeConfig struct {
+	prefetch int
+}
+
+// WithPrefetch makes the merge pull from each input sequence concurrently,
+// buffering up to n batches per input ahead of the merge loop. This trades
+// memory for overlapping I/O (or other per-batch latency) across inputs
+// instead of stalling on one input at a time. A value of 0 (the default)
+// disables prefetching.
+func WithPrefetch(n int) MergeOption {
+	return func(c *mergeConfig) { c.prefetch = n }
+}
+
+// MergeOpts is like Merge but accepts MergeOptions controlling how the
+// sequences are pulled from. Unlike the rest of the package, seqs is a plain
+// slice rather than variadic: MergeOption needs the trailing variadic slot.
+func MergeOpts[V cmp.Ordered](seqs []iter.Seq[V], opts ...MergeOption) iter.Seq[V] {
+	return MergeFuncOpts(cmp.Compare[V], seqs, opts...)
+}
+
+// MergeFuncOpts is like MergeFunc but accepts MergeOptions controlling how
+// the sequences are pulled from. With WithPrefetch(n) and more than two
+// sequences, each sequence is pulled from its own goroutine so that I/O on
+// one input overlaps with I/O on the others; otherwise it falls back to the
+// inline behavior of MergeFunc, where the overhead of prefetching isn't
+// worth it. Unlike the rest of the package, seqs is a plain slice rather
+// than variadic: MergeOption needs the trailing variadic slot.
+func MergeFuncOpts[V any](cmp func(V, V) int, seqs []iter.Seq[V], opts ...MergeOption) iter.Seq[V] {
+	var cfg mergeConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.prefetch <= 0 || len(seqs) <= 2 {
+		return MergeFunc(cmp, seqs...)
+	}
+
+	bufferedSeqs := make([]iter.Seq[[]V], len(seqs))
+	for i, seq := range seqs {
+		bufferedSeqs[i] = bufferedFunc(bufferSize, seq)
+	}
+	return debuffer(mergePrefetch(cmp, bufferedSeqs, cfg.prefetch))
+}
+
+// prefetchedBatch carries either a batch pulled from an input sequence, or a
+// panic value recovered from the goroutine producing it, so that the panic
+// can be re-raised on the consuming side.
+type prefetchedBatch[V any] struct {
+	values []V
+	panic  any
+}
+
+// mergePrefetch merges already-batched sequences the same way merge does,
+// except each input is pulled from its own goroutine into a channel of the
+// given depth, so that a slow input doesn't stall the merge loop while the
+// others are ready.
+//
+//go:noinline
+func mergePrefetch[V any](cmp func(V, V) int, seqs []iter.Seq[[]V], depth int) iter.Seq[[]V] {
+	return func(yield func([]V) bool) {
+		done := make(chan struct{})
+		var wg sync.WaitGroup
+		channels := make([]chan prefetchedBatch[V], len(seqs))
+
+		for i, seq := range seqs {
+			ch := make(chan prefetchedBatch[V], depth)
+			channels[i] = ch
+			wg.Add(1)
+			go prefetch(&wg, done, ch, seq)
+		}
+
+		pulledSeqs := make([]iter.Seq[[]V], len(channels))
+		for i, ch := range channels {
+			pulledSeqs[i] = channelSeq(ch)
+		}
+
+		tree := makeTree(pulledSeqs...)
+		// Defers run LIFO: close(done) must unblock every producer stuck
+		// sending on a full channel before wg.Wait() blocks on them, and
+		// producers must have exited before tree.stop() tears down the
+		// pulledSeqs iterators they feed.
+		defer tree.stop()
+		defer wg.Wait()
+		defer close(done)
+
+		buffer := make([]V, bufferSize)
+		offset := 0
+
+		for {
+			v, ok := tree.next(cmp)
+			if !ok {
+				break
+			}
+			buffer[offset] = v
+			offset++
+			if offset == len(buffer) {
+				if !yield(buffer) {
+					return
+				}
+				offset = 0
+			}
+		}
+
+		if offset > 0 {
+			yield(buffer[:offset])
+		}
+	}
+}
+
+// prefetch runs seq to completion, forwarding every batch it produces onto
+// ch. It stops as soon as done is closed, which happens when the merge
+// loop's output is abandoned early, so that producers never outlive their
+// consumer. Panics from seq are recovered and forwarded on ch so the
+// consumer can re-raise them in its own goroutine.
+func prefetch[V any](wg *sync.WaitGroup, done <-chan struct{}, ch chan<- prefetchedBatch[V], seq iter.Seq[[]V]) {
+	defer wg.Done()
+	defer close(ch)
+	defer func() {
+		if r := recover(); r != nil {
+			select {
+			case ch <- prefetchedBatch[V]{panic: r}:
+			case <-done:
+			}
+		}
+	}()
+
+	next, stop := iter.Pull(seq)
+	defer stop()
+
+	for {
+		values, ok := next()
+		if !ok {
+			return
+		}
+		select {
+		case ch <- prefetchedBatch[V]{values: values}:
+		case <-done:
+			return
+		}
+	}
+}
+
+// channelSeq replays the batches written to ch as a sequence, re-raising any
+// panic recovered from the producer goroutine.
+func channelSeq[V any](ch <-chan prefetchedBatch[V]) iter.Seq[[]V] {
+	return func(yield func([]V) bool) {
+		for b := range ch {
+			if b.panic != nil {
+				panic(b.panic)
+			}
+			if !yield(b.values) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package kway
+
+import (
+	"cmp"
+	"iter"
+)
+
+// kv pairs up a key and a value so that key/value sequences can be merged by
+// reusing the value-only merge machinery without boxing either half of the
+// pair.
+type kv[K, V any] struct {
+	Key   K
+	Value V
+}
+
+// MergeSeq2 merges multiple key/value sequences into one, ordered by key.
+// The sequences must produce keys in ascending order. When multiple
+// sequences produce equal keys, the pairs are emitted in the order of the
+// input sequences, making the merge stable.
+func MergeSeq2[K cmp.Ordered, V any](seqs ...iter.Seq2[K, V]) iter.Seq2[K, V] {
+	return MergeSeq2Func(cmp.Compare[K], seqs...)
+}
+
+// MergeSeq2Func merges multiple key/value sequences into one using the given
+// comparison function to determine the order of keys. The sequences must be
+// ordered by the same comparison function. Ties are broken by the index of
+// the input sequence, making the merge stable.
+func MergeSeq2Func[K, V any](cmp func(K, K) int, seqs ...iter.Seq2[K, V]) iter.Seq2[K, V] {
+	switch len(seqs) {
+	case 0:
+		return func(func(K, V) bool) {}
+	case 1:
+		return seqs[0]
+	case 2:
+		seq0 := bufferedFunc(bufferSize, seq2ToKV(seqs[0]))
+		seq1 := bufferedFunc(bufferSize, seq2ToKV(seqs[1]))
+		return kvToSeq2(mergeBatches2(compareKV[K, V](cmp), seq0, seq1))
+	default:
+		bufferedSeqs := make([]iter.Seq[[]kv[K, V]], len(seqs))
+		for i, seq := range seqs {
+			bufferedSeqs[i] = bufferedFunc(bufferSize, seq2ToKV(seq))
+		}
+		return kvToSeq2(merge(compareKV[K, V](cmp), bufferedSeqs))
+	}
+}
+
+// MergeSlice2 merges multiple batched key/value sequences into one, ordered
+// by key. Each yielded pair of slices must have matching lengths and must be
+// ordered by key within and across batches.
+func MergeSlice2[K cmp.Ordered, V any](seqs ...iter.Seq2[[]K, []V]) iter.Seq2[[]K, []V] {
+	return MergeSlice2Func(cmp.Compare[K], seqs...)
+}
+
+// MergeSlice2Func merges multiple batched key/value sequences into one using
+// the given comparison function to determine the order of keys.
+func MergeSlice2Func[K, V any](cmp func(K, K) int, seqs ...iter.Seq2[[]K, []V]) iter.Seq2[[]K, []V] {
+	switch len(seqs) {
+	case 0:
+		return func(func([]K, []V) bool) {}
+	case 1:
+		return seqs[0]
+	case 2:
+		return kvToSlice2(mergeBatches2(compareKV[K, V](cmp), slice2ToKV(seqs[0]), slice2ToKV(seqs[1])))
+	default:
+		bufferedSeqs := make([]iter.Seq[[]kv[K, V]], len(seqs))
+		for i, seq := range seqs {
+			bufferedSeqs[i] = slice2ToKV(seq)
+		}
+		return kvToSlice2(merge(compareKV[K, V](cmp), bufferedSeqs))
+	}
+}
+
+// compareKV adapts a key comparison function to compare the Key field of two
+// kv pairs, so that cmp and value merges never need to know about V.
+func compareKV[K, V any](cmp func(K, K) int) func(kv[K, V], kv[K, V]) int {
+	return func(a, b kv[K, V]) int { return cmp(a.Key, b.Key) }
+}
+
+// seq2ToKV adapts a key/value sequence to a sequence of kv pairs so it can be
+// buffered and merged with the value-only machinery.
+func seq2ToKV[K, V any](seq iter.Seq2[K, V]) iter.Seq[kv[K, V]] {
+	return func(yield func(kv[K, V]) bool) {
+		seq(func(k K, v V) bool {
+			return yield(kv[K, V]{Key: k, Value: v})
+		})
+	}
+}
+
+// slice2ToKV zips the parallel key/value batches produced by a batched
+// key/value sequence into batches of kv pairs.
+func slice2ToKV[K, V any](seq iter.Seq2[[]K, []V]) iter.Seq[[]kv[K, V]] {
+	return func(yield func([]kv[K, V]) bool) {
+		seq(func(keys []K, values []V) bool {
+			pairs := make([]kv[K, V], len(keys))
+			for i := range keys {
+				pairs[i] = kv[K, V]{Key: keys[i], Value: values[i]}
+			}
+			return yield(pairs)
+		})
+	}
+}
+
+// kvToSeq2 unzips a sequence of kv pair batches back into a key/value
+// sequence.
+func kvToSeq2[K, V any](seq iter.Seq[[]kv[K, V]]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		seq(func(pairs []kv[K, V]) bool {
+			for _, pair := range pairs {
+				if !yield(pair.Key, pair.Value) {
+					return false
+				}
+			}
+			return true
+		})
+	}
+}
+
+// kvToSlice2 unzips a sequence of kv pair batches back into a batched
+// key/value sequence, keeping each batch's keys and values parallel.
+func kvToSlice2[K, V any](seq iter.Seq[[]kv[K, V]]) iter.Seq2[[]K, []V] {
+	return func(yield func([]K, []V) bool) {
+		seq(func(pairs []kv[K, V]) bool {
+			keys := make([]K, len(pairs))
+			values := make([]V, len(pairs))
+			for i, pair := range pairs {
+				keys[i] = pair.Key
+				values[i] = pair.Value
+			}
+			return yield(keys, values)
+		})
+	}
+}
+
+// mergeBatches2 is a 2-way buffered merge fast path used in place of the
+// package's merge2 for new call sites: i0/i1 live outside the refill loop,
+// so a batch only ever advances past the index it has actually been
+// compared up to, and refilling one side never replays the elements already
+// emitted from the other side's still-partially-consumed batch.
+//
+//go:noinline
+func mergeBatches2[V any](cmp func(V, V) int, seq0, seq1 iter.Seq[[]V]) iter.Seq[[]V] {
+	return func(yield func([]V) bool) {
+		next0, stop0 := iter.Pull(seq0)
+		defer stop0()
+
+		next1, stop1 := iter.Pull(seq1)
+		defer stop1()
+
+		values0, ok0 := next0()
+		values1, ok1 := next1()
+		i0, i1 := 0, 0
+		buffer := make([]V, bufferSize)
+		offset := 0
+
+		emit := func(v V) bool {
+			if offset >= len(buffer) {
+				if !yield(buffer[:offset]) {
+					return false
+				}
+				offset = 0
+			}
+			buffer[offset] = v
+			offset++
+			return true
+		}
+
+		for ok0 && ok1 {
+			if i0 == len(values0) {
+				values0, ok0 = next0()
+				i0 = 0
+				continue
+			}
+			if i1 == len(values1) {
+				values1, ok1 = next1()
+				i1 = 0
+				continue
+			}
+
+			v0 := values0[i0]
+			v1 := values1[i1]
+
+			diff := cmp(v0, v1)
+			switch {
+			case diff <= 0:
+				if !emit(v0) {
+					return
+				}
+				i0++
+			default:
+				if !emit(v1) {
+					return
+				}
+				i1++
+			}
+		}
+
+		if !flushBatches(emit, next0, values0[i0:], ok0) {
+			return
+		}
+		if !flushBatches(emit, next1, values1[i1:], ok1) {
+			return
+		}
+
+		if offset > 0 {
+			yield(buffer[:offset])
+		}
+	}
+}
+
+// flushBatches emits the unconsumed suffix of an input's current batch, then
+// its remaining batches, stopping as soon as emit reports it should.
+func flushBatches[V any](emit func(V) bool, next func() ([]V, bool), values []V, ok bool) bool {
+	for ok {
+		for _, v := range values {
+			if !emit(v) {
+				return false
+			}
+		}
+		values, ok = next()
+	}
+	return true
+}
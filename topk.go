@@ -0,0 +1,203 @@
+package kway
+
+import (
+	"cmp"
+	"iter"
+)
+
+// MergeTopK merges multiple sorted sequences into one, yielding at most the
+// first k elements of the merge and then stopping. The sequences must
+// produce ordered values.
+func MergeTopK[V cmp.Ordered](k int, seqs ...iter.Seq[V]) iter.Seq[V] {
+	return MergeTopKFunc(k, cmp.Compare[V], seqs...)
+}
+
+// MergeTopKFunc merges multiple sorted sequences into one using the given
+// comparison function, yielding at most the first k elements of the merge
+// and then stopping. Because only a k-sized prefix is needed, every input is
+// stopped as soon as it can no longer contribute to that prefix.
+func MergeTopKFunc[V any](k int, cmp func(V, V) int, seqs ...iter.Seq[V]) iter.Seq[V] {
+	if k <= 0 {
+		return func(func(V) bool) {}
+	}
+	switch len(seqs) {
+	case 0:
+		return func(func(V) bool) {}
+	case 1:
+		return limitSeq(k, seqs[0])
+	case 2:
+		seq0 := bufferedFunc(bufferSize, seqs[0])
+		seq1 := bufferedFunc(bufferSize, seqs[1])
+		return debuffer(topK2(cmp, k, seq0, seq1))
+	default:
+		bufferedSeqs := make([]iter.Seq[[]V], len(seqs))
+		for i, seq := range seqs {
+			bufferedSeqs[i] = bufferedFunc(bufferSize, seq)
+		}
+		return debuffer(topK(cmp, k, bufferedSeqs))
+	}
+}
+
+// limitSeq stops seq after its k-th value.
+func limitSeq[V any](k int, seq iter.Seq[V]) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		n := 0
+		seq(func(v V) bool {
+			if !yield(v) {
+				return false
+			}
+			n++
+			return n < k
+		})
+	}
+}
+
+// topK2 is the 2-way buffered merge fast path used by MergeTopKFunc. It
+// mirrors merge2, additionally counting emitted values so it can stop
+// pulling from both inputs as soon as k values have been produced.
+//
+// Unlike merge2, i0/i1 live outside the refill loop: a batch is only ever
+// advanced past the index it has actually been compared up to, so refilling
+// one side never replays the elements already emitted from the other side's
+// still-partially-consumed batch.
+//
+//go:noinline
+func topK2[V any](cmp func(V, V) int, k int, seq0, seq1 iter.Seq[[]V]) iter.Seq[[]V] {
+	return func(yield func([]V) bool) {
+		next0, stop0 := iter.Pull(seq0)
+		defer stop0()
+
+		next1, stop1 := iter.Pull(seq1)
+		defer stop1()
+
+		values0, ok0 := next0()
+		values1, ok1 := next1()
+		i0, i1 := 0, 0
+		buffer := make([]V, bufferSize)
+		offset := 0
+		n := 0
+
+		// emit reports whether the caller should keep producing values. It
+		// returns false both when k has been reached (having already
+		// flushed any buffered values) and when the consumer stopped
+		// reading early, so every call site can just `return` on false.
+		emit := func(v V) bool {
+			if offset >= len(buffer) {
+				if !yield(buffer[:offset]) {
+					return false
+				}
+				offset = 0
+			}
+			buffer[offset] = v
+			offset++
+			n++
+			if n >= k {
+				if offset > 0 {
+					yield(buffer[:offset])
+				}
+				return false
+			}
+			return true
+		}
+
+		for ok0 && ok1 {
+			if i0 == len(values0) {
+				values0, ok0 = next0()
+				i0 = 0
+				continue
+			}
+			if i1 == len(values1) {
+				values1, ok1 = next1()
+				i1 = 0
+				continue
+			}
+
+			v0 := values0[i0]
+			v1 := values1[i1]
+
+			diff := cmp(v0, v1)
+			switch {
+			case diff < 0:
+				if !emit(v0) {
+					return
+				}
+				i0++
+			case diff > 0:
+				if !emit(v1) {
+					return
+				}
+				i1++
+			default:
+				if !emit(v0) {
+					return
+				}
+				i0++
+				if !emit(v1) {
+					return
+				}
+				i1++
+			}
+		}
+
+		if ok0 && !flushTopK(emit, next0, values0[i0:], ok0) {
+			return
+		}
+		if ok1 && !flushTopK(emit, next1, values1[i1:], ok1) {
+			return
+		}
+
+		if offset > 0 {
+			yield(buffer[:offset])
+		}
+	}
+}
+
+// flushTopK emits the unconsumed suffix of an input's current batch, then
+// its remaining batches, stopping as soon as emit reports it should.
+func flushTopK[V any](emit func(V) bool, next func() ([]V, bool), values []V, ok bool) bool {
+	for ok {
+		for _, v := range values {
+			if !emit(v) {
+				return false
+			}
+		}
+		values, ok = next()
+	}
+	return true
+}
+
+// topK merges more than two sequences using the loser tree, stopping as soon
+// as k values have been produced. The deferred tree.stop() then promptly
+// cancels the iter.Pull of every remaining input.
+//
+//go:noinline
+func topK[V any](cmp func(V, V) int, k int, seqs []iter.Seq[[]V]) iter.Seq[[]V] {
+	return func(yield func([]V) bool) {
+		tree := makeTree(seqs...)
+		defer tree.stop()
+
+		buffer := make([]V, bufferSize)
+		offset := 0
+		n := 0
+
+		for n < k {
+			v, ok := tree.next(cmp)
+			if !ok {
+				break
+			}
+			buffer[offset] = v
+			offset++
+			n++
+			if offset == len(buffer) {
+				if !yield(buffer) {
+					return
+				}
+				offset = 0
+			}
+		}
+
+		if offset > 0 {
+			yield(buffer[:offset])
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package kway
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"slices"
+	"sort"
+	"testing"
+)
+
+// stridedSeqs returns n sequences that together enumerate [0, total) in
+// ascending order when merged: sequence i yields i, i+n, i+2n, ...
+func stridedSeqs(n, total int) []iter.Seq[int] {
+	seqs := make([]iter.Seq[int], n)
+	for i := 0; i < n; i++ {
+		i := i
+		seqs[i] = func(yield func(int) bool) {
+			for v := i; v < total; v += n {
+				if !yield(v) {
+					return
+				}
+			}
+		}
+	}
+	return seqs
+}
+
+// BenchmarkMergeTopKFunc merges n sorted sequences covering [0, total) and
+// takes the first k results. Because MergeTopKFunc stops pulling from every
+// input as soon as k values have been produced, its cost should stay roughly
+// proportional to k*log(n) and essentially flat as total grows, unlike a
+// full Merge over the same inputs.
+func BenchmarkMergeTopKFunc(b *testing.B) {
+	for _, n := range []int{4, 16, 64} {
+		for _, total := range []int{1_000, 1_000_000} {
+			for _, k := range []int{10, 100} {
+				b.Run(fmt.Sprintf("n=%d/total=%d/k=%d", n, total, k), func(b *testing.B) {
+					seqs := stridedSeqs(n, total)
+					b.ResetTimer()
+					for i := 0; i < b.N; i++ {
+						for range MergeTopKFunc(k, cmp.Compare[int], seqs...) {
+						}
+					}
+				})
+			}
+		}
+	}
+}
+
+// BenchmarkMergeFunc merges the same inputs as BenchmarkMergeTopKFunc in
+// full, for comparison: unlike MergeTopKFunc, its cost grows with total.
+func BenchmarkMergeFunc(b *testing.B) {
+	for _, n := range []int{4, 16, 64} {
+		for _, total := range []int{1_000, 1_000_000} {
+			b.Run(fmt.Sprintf("n=%d/total=%d", n, total), func(b *testing.B) {
+				seqs := stridedSeqs(n, total)
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					for range MergeFunc(cmp.Compare[int], seqs...) {
+					}
+				}
+			})
+		}
+	}
+}
+
+// naiveMerge merges inputs by concatenating and sorting them, as a reference
+// to check MergeFunc-family implementations against.
+func naiveMerge(inputs [][]int) []int {
+	var all []int
+	for _, in := range inputs {
+		all = append(all, in...)
+	}
+	sort.Ints(all)
+	return all
+}
+
+func naiveTopK(k int, inputs [][]int) []int {
+	all := naiveMerge(inputs)
+	if k < len(all) {
+		all = all[:k]
+	}
+	return all
+}
+
+func naiveUnique(inputs [][]int) []int {
+	all := naiveMerge(inputs)
+	out := all[:0:0]
+	for i, v := range all {
+		if i == 0 || v != all[i-1] {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func intSeqs(inputs [][]int) []iter.Seq[int] {
+	seqs := make([]iter.Seq[int], len(inputs))
+	for i, values := range inputs {
+		seqs[i] = slices.Values(values)
+	}
+	return seqs
+}
+
+// TestMergeTopKFunc exercises the 2-way fast path with inputs of uneven
+// length, including lengths that span more than one internal batch, to guard
+// against replaying an already-emitted element when one side's batch is
+// refilled while the other's is still partially consumed.
+func TestMergeTopKFunc(t *testing.T) {
+	uneven := [][]int{{1, 2}, seqRange(3, 20)}
+	manyBatches := [][]int{seqRangeStep(0, 600, 2), seqRangeStep(1, 600, 2)}
+
+	cases := []struct {
+		name   string
+		inputs [][]int
+		k      int
+	}{
+		{"uneven lengths", uneven, 5},
+		{"uneven lengths, k covers all", uneven, 19},
+		{"many batches", manyBatches, 400},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := Collect(MergeTopKFunc(c.k, cmp.Compare[int], intSeqs(c.inputs)...))
+			want := naiveTopK(c.k, c.inputs)
+			if !slices.Equal(got, want) {
+				t.Errorf("MergeTopKFunc(%d, ...) = %v, want %v", c.k, got, want)
+			}
+		})
+	}
+}
+
+// TestMergeUniqueFunc guards against the same uneven-batch replay bug
+// surfacing as duplicate output from the "deduplicating" merge.
+func TestMergeUniqueFunc(t *testing.T) {
+	inputs := [][]int{
+		{1, 2, 2, 3, 5},
+		{2, 3, 4, 5, 5},
+	}
+	got := Collect(MergeUniqueFunc(cmp.Compare[int], intSeqs(inputs)...))
+	want := naiveUnique(inputs)
+	if !slices.Equal(got, want) {
+		t.Errorf("MergeUniqueFunc(...) = %v, want %v", got, want)
+	}
+}
+
+type countRecord struct {
+	Key   int
+	Count int
+}
+
+func compareCountRecord(a, b countRecord) int { return cmp.Compare(a.Key, b.Key) }
+
+func sumCountRecord(a, b countRecord) countRecord { return countRecord{a.Key, a.Count + b.Count} }
+
+// TestMergeReduceFunc checks the aggregate-merge use case the request calls
+// out: counts for the same key from different sides must be summed exactly
+// once, not replayed by the 2-way fast path.
+func TestMergeReduceFunc(t *testing.T) {
+	seq0 := slices.Values([]countRecord{{1, 1}, {2, 1}})
+	seq1 := slices.Values([]countRecord{{2, 1}, {3, 1}})
+
+	got := Collect(MergeReduceFunc(compareCountRecord, sumCountRecord, seq0, seq1))
+	want := []countRecord{{1, 1}, {2, 2}, {3, 1}}
+	if !slices.Equal(got, want) {
+		t.Errorf("MergeReduceFunc(...) = %v, want %v", got, want)
+	}
+}
+
+func seqRange(start, end int) []int {
+	values := make([]int, 0, end-start)
+	for v := start; v < end; v++ {
+		values = append(values, v)
+	}
+	return values
+}
+
+func seqRangeStep(start, end, step int) []int {
+	values := make([]int, 0, (end-start)/step)
+	for v := start; v < end; v += step {
+		values = append(values, v)
+	}
+	return values
+}
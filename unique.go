@@ -0,0 +1,243 @@
+package kway
+
+import (
+	"cmp"
+	"iter"
+)
+
+// MergeUnique merges multiple sorted sequences into one, collapsing runs of
+// equal values down to a single occurrence. The sequences must produce
+// ordered values.
+func MergeUnique[V cmp.Ordered](seqs ...iter.Seq[V]) iter.Seq[V] {
+	return MergeUniqueFunc(cmp.Compare[V], seqs...)
+}
+
+// MergeUniqueFunc merges multiple sorted sequences into one using the given
+// comparison function, collapsing runs of equal values down to a single
+// occurrence. When values compare equal, the one produced by the earliest
+// sequence is kept.
+func MergeUniqueFunc[V any](cmp func(V, V) int, seqs ...iter.Seq[V]) iter.Seq[V] {
+	return MergeReduceFunc(cmp, keepFirst[V], seqs...)
+}
+
+// MergeReduceFunc merges multiple sorted sequences into one using the given
+// comparison function, combining runs of equal values with reduce instead of
+// emitting them all. reduce is called with the value accumulated so far and
+// the next equal value, in the order the sequences were given.
+func MergeReduceFunc[V any](cmp func(V, V) int, reduce func(V, V) V, seqs ...iter.Seq[V]) iter.Seq[V] {
+	switch len(seqs) {
+	case 0:
+		return func(func(V) bool) {}
+	case 1:
+		return debuffer(reduceFilter(cmp, reduce, bufferedFunc(bufferSize, seqs[0])))
+	case 2:
+		seq0 := bufferedFunc(bufferSize, seqs[0])
+		seq1 := bufferedFunc(bufferSize, seqs[1])
+		return debuffer(mergeReduce2(cmp, reduce, seq0, seq1))
+	default:
+		bufferedSeqs := make([]iter.Seq[[]V], len(seqs))
+		for i, seq := range seqs {
+			bufferedSeqs[i] = bufferedFunc(bufferSize, seq)
+		}
+		return debuffer(reduceFilter(cmp, reduce, merge(cmp, bufferedSeqs)))
+	}
+}
+
+// MergeSliceUnique is the batched-slice equivalent of MergeUnique.
+func MergeSliceUnique[V cmp.Ordered](seqs ...iter.Seq[[]V]) iter.Seq[[]V] {
+	return MergeSliceUniqueFunc(cmp.Compare[V], seqs...)
+}
+
+// MergeSliceUniqueFunc is the batched-slice equivalent of MergeUniqueFunc.
+func MergeSliceUniqueFunc[V any](cmp func(V, V) int, seqs ...iter.Seq[[]V]) iter.Seq[[]V] {
+	switch len(seqs) {
+	case 0:
+		return func(func([]V) bool) {}
+	case 1:
+		return reduceFilter(cmp, keepFirst[V], seqs[0])
+	case 2:
+		return mergeReduce2(cmp, keepFirst[V], seqs[0], seqs[1])
+	default:
+		return reduceFilter(cmp, keepFirst[V], merge(cmp, seqs))
+	}
+}
+
+func keepFirst[V any](v, _ V) V { return v }
+
+// mergeReduce2 is the 2-way buffered merge fast path used by MergeReduceFunc
+// and MergeSliceUniqueFunc. Unlike merge2, it holds back the last produced
+// value (pending) so that a value equal to it, whichever side it comes from,
+// is folded into it with reduce instead of being written to the output
+// buffer.
+//
+// i0/i1 live outside the refill loop, same as topK2: a batch only ever
+// advances past the index it has actually been compared up to, so refilling
+// one side never replays the elements already folded in from the other
+// side's still-partially-consumed batch.
+//
+//go:noinline
+func mergeReduce2[V any](cmp func(V, V) int, reduce func(V, V) V, seq0, seq1 iter.Seq[[]V]) iter.Seq[[]V] {
+	return func(yield func([]V) bool) {
+		next0, stop0 := iter.Pull(seq0)
+		defer stop0()
+
+		next1, stop1 := iter.Pull(seq1)
+		defer stop1()
+
+		values0, ok0 := next0()
+		values1, ok1 := next1()
+		i0, i1 := 0, 0
+		buffer := make([]V, bufferSize)
+		offset := 0
+		var pending V
+		hasPending := false
+
+		emit := func(v V) bool {
+			if hasPending && cmp(pending, v) == 0 {
+				pending = reduce(pending, v)
+				return true
+			}
+			if hasPending {
+				if offset >= len(buffer) {
+					if !yield(buffer[:offset]) {
+						return false
+					}
+					offset = 0
+				}
+				buffer[offset] = pending
+				offset++
+			}
+			pending = v
+			hasPending = true
+			return true
+		}
+
+		for ok0 && ok1 {
+			if i0 == len(values0) {
+				values0, ok0 = next0()
+				i0 = 0
+				continue
+			}
+			if i1 == len(values1) {
+				values1, ok1 = next1()
+				i1 = 0
+				continue
+			}
+
+			v0 := values0[i0]
+			v1 := values1[i1]
+
+			diff := cmp(v0, v1)
+			switch {
+			case diff < 0:
+				if !emit(v0) {
+					return
+				}
+				i0++
+			case diff > 0:
+				if !emit(v1) {
+					return
+				}
+				i1++
+			default:
+				if !emit(reduce(v0, v1)) {
+					return
+				}
+				i0++
+				i1++
+			}
+		}
+
+		if !flushReduce(emit, next0, values0[i0:], ok0) {
+			return
+		}
+		if !flushReduce(emit, next1, values1[i1:], ok1) {
+			return
+		}
+
+		if hasPending {
+			if offset >= len(buffer) {
+				if !yield(buffer[:offset]) {
+					return
+				}
+				offset = 0
+			}
+			buffer[offset] = pending
+			offset++
+		}
+
+		if offset > 0 {
+			yield(buffer[:offset])
+		}
+	}
+}
+
+func flushReduce[V any](emit func(V) bool, next func() ([]V, bool), values []V, ok bool) bool {
+	for ok {
+		for _, v := range values {
+			if !emit(v) {
+				return false
+			}
+		}
+		values, ok = next()
+	}
+	return true
+}
+
+// reduceFilter wraps an ordered batched sequence (the N-way merge output, or
+// a single input sequence), folding runs of equal values down to a single
+// value with reduce. Because merge yields values in total order, equal values
+// are always adjacent regardless of which input sequence they came from.
+//
+//go:noinline
+func reduceFilter[V any](cmp func(V, V) int, reduce func(V, V) V, seq iter.Seq[[]V]) iter.Seq[[]V] {
+	return func(yield func([]V) bool) {
+		buffer := make([]V, bufferSize)
+		offset := 0
+		var pending V
+		hasPending := false
+		stopped := false
+
+		seq(func(values []V) bool {
+			for _, v := range values {
+				if hasPending && cmp(pending, v) == 0 {
+					pending = reduce(pending, v)
+					continue
+				}
+				if hasPending {
+					if offset >= len(buffer) {
+						if !yield(buffer[:offset]) {
+							stopped = true
+							return false
+						}
+						offset = 0
+					}
+					buffer[offset] = pending
+					offset++
+				}
+				pending = v
+				hasPending = true
+			}
+			return true
+		})
+
+		if stopped {
+			return
+		}
+
+		if hasPending {
+			if offset >= len(buffer) {
+				if !yield(buffer[:offset]) {
+					return
+				}
+				offset = 0
+			}
+			buffer[offset] = pending
+			offset++
+		}
+
+		if offset > 0 {
+			yield(buffer[:offset])
+		}
+	}
+}